@@ -0,0 +1,21 @@
+package rest
+
+// Cache is the interface a cache backend must implement to be used by
+// RequestBuilder in place of the package's in-process resourceCache.
+// Implementations must be safe for concurrent use.
+type Cache interface {
+	Get(key string) (*Response, bool)
+	Set(key string, resp *Response)
+	Delete(key string)
+}
+
+var _ Cache = (*responseCache)(nil)
+
+// getCache returns the Cache backend configured on the RequestBuilder,
+// falling back to the package-level in-process cache when none was set.
+func (rb *RequestBuilder) getCache() Cache {
+	if rb.Cache != nil {
+		return rb.Cache
+	}
+	return resourceCache
+}
@@ -0,0 +1,61 @@
+package rest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// DiskCache is a Cache backend that persists responses as files under Dir,
+// keyed by the sha256 hash of the cache key. It suits single-process
+// deployments that want cached responses to survive restarts.
+type DiskCache struct {
+	Dir string
+}
+
+// NewDiskCache returns a DiskCache that stores entries under dir, creating
+// it on first write if necessary.
+func NewDiskCache(dir string) *DiskCache {
+	return &DiskCache{Dir: dir}
+}
+
+func (c *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:]))
+}
+
+// Get implements Cache.
+func (c *DiskCache) Get(key string) (*Response, bool) {
+	data, err := ioutil.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	resp, err := UnmarshalCache(data)
+	if err != nil {
+		return nil, false
+	}
+
+	return resp, true
+}
+
+// Set implements Cache.
+func (c *DiskCache) Set(key string, resp *Response) {
+	data, err := resp.MarshalCache()
+	if err != nil {
+		return
+	}
+
+	if err := os.MkdirAll(c.Dir, 0o755); err != nil {
+		return
+	}
+
+	_ = ioutil.WriteFile(c.path(key), data, 0o644)
+}
+
+// Delete implements Cache.
+func (c *DiskCache) Delete(key string) {
+	_ = os.Remove(c.path(key))
+}
@@ -0,0 +1,102 @@
+package rest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// varyMarkerSuffix keys the cache entry that remembers which Vary header a
+// URL's responses have advertised, so a later request can compute the
+// right variant key before it knows the response.
+const varyMarkerSuffix = "\x00vary"
+
+// cacheKey returns the key used to read/write the cache entry for url,
+// folding in the request header values named by any Vary header a previous
+// response for url advertised, so negotiated variants don't collide.
+func (rb *RequestBuilder) cacheKey(cache Cache, url string, reqHeader http.Header) string {
+	marker, ok := cache.Get(url + varyMarkerSuffix)
+	if !ok || marker == nil {
+		return url
+	}
+
+	return varyKey(url, reqHeader, marker.Header.Get("Vary"))
+}
+
+func varyKey(url string, reqHeader http.Header, vary string) string {
+	if vary == "" {
+		return url
+	}
+
+	names := strings.Split(vary, ",")
+	for i := range names {
+		names[i] = strings.ToLower(strings.TrimSpace(names[i]))
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString(url)
+	for _, name := range names {
+		b.WriteByte('\x00')
+		b.WriteString(name)
+		b.WriteByte('=')
+		b.WriteString(reqHeader.Get(name))
+	}
+
+	return b.String()
+}
+
+// rememberVary records, under url's marker key, the Vary header a response
+// advertised so future requests for url can compute their variant key.
+func rememberVary(cache Cache, url string, vary string) {
+	if vary == "" {
+		return
+	}
+
+	marker := &Response{Response: &http.Response{Header: http.Header{"Vary": []string{vary}}}}
+	cache.Set(url+varyMarkerSuffix, marker)
+}
+
+// cacheableVerb reports whether verb/body may participate in the response
+// cache: GET-like verbs always can; POST/PUT can too when CachePOST is
+// enabled, provided the body isn't a GraphQL mutation.
+func (rb *RequestBuilder) cacheableVerb(verb string, body []byte) bool {
+	if match(verb, readVerbs) {
+		return true
+	}
+
+	if !rb.CachePOST || !match(verb, cacheableContentVerbs) {
+		return false
+	}
+
+	if isGraphQL, mutation := isGraphQLMutation(rb.ContentType, body); isGraphQL {
+		return !mutation
+	}
+
+	return true
+}
+
+// resolveCacheKey returns the cache key for verb/url/body: GET-like verbs
+// use the Vary-aware URL key, while a cacheable POST/PUT (see CachePOST) is
+// keyed by its body hash so distinct queries don't collide.
+func (rb *RequestBuilder) resolveCacheKey(cache Cache, verb, url string, body []byte, reqHeader http.Header) string {
+	if match(verb, cacheableContentVerbs) {
+		if isGraphQL, query, variables := parseGraphQLBody(rb.ContentType, body); isGraphQL {
+			return graphQLCacheKey(verb, url, query, variables)
+		}
+
+		return sha256CacheKey(verb, url, body)
+	}
+
+	return rb.cacheKey(cache, url, reqHeader)
+}
+
+// sha256CacheKey keys a POST/PUT cache entry by method, URL and the sha256
+// of body. For a GraphQL query, graphQLCacheKey passes the normalized query
+// and variables here as body.
+func sha256CacheKey(verb, url string, body []byte) string {
+	sum := sha256.Sum256(body)
+	return verb + " " + url + " " + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,81 @@
+package rest
+
+import (
+	"net/http"
+	"regexp"
+	"time"
+)
+
+// CacheRuleType selects what a CacheRule matches against.
+type CacheRuleType int
+
+const (
+	// CacheRulePath matches Value, a regex, against the outgoing request URL.
+	CacheRulePath CacheRuleType = iota
+	// CacheRuleHeader matches Value against the request header named HeaderKey.
+	CacheRuleHeader
+)
+
+// CacheRuleAction is what happens to a request matched by a CacheRule.
+type CacheRuleAction int
+
+const (
+	// CacheRuleCache forces the request to participate in caching even if
+	// RequestBuilder.DisableCache is set.
+	CacheRuleCache CacheRuleAction = iota
+	// CacheRuleSkip excludes the request from caching entirely.
+	CacheRuleSkip
+	// CacheRuleTTLOverride caches the response for TTL regardless of the
+	// origin's own Cache-Control/Expires headers.
+	CacheRuleTTLOverride
+)
+
+// CacheRule lets a RequestBuilder pin or exclude specific endpoints from
+// caching without writing a wrapping layer around doRequest. Rules are
+// evaluated in order; the first match wins.
+type CacheRule struct {
+	Type      CacheRuleType
+	Value     string
+	HeaderKey string
+	Action    CacheRuleAction
+
+	// TTL is the forced freshness lifetime for a CacheRuleTTLOverride rule.
+	TTL time.Duration
+}
+
+func (r CacheRule) matches(reqURL string, header http.Header) bool {
+	switch r.Type {
+	case CacheRulePath:
+		matched, err := regexp.MatchString(r.Value, reqURL)
+		return err == nil && matched
+	case CacheRuleHeader:
+		return header.Get(r.HeaderKey) == r.Value
+	default:
+		return false
+	}
+}
+
+// matchCacheRule returns the first of rb.CacheRules that matches reqURL and
+// header.
+func (rb *RequestBuilder) matchCacheRule(reqURL string, header http.Header) (CacheRule, bool) {
+	for _, rule := range rb.CacheRules {
+		if rule.matches(reqURL, header) {
+			return rule, true
+		}
+	}
+
+	return CacheRule{}, false
+}
+
+// effectiveDisableCache applies rb.CacheRules on top of rb.DisableCache: a
+// matching Skip rule disables caching for reqURL even if DisableCache is
+// false, and a matching Cache/TTLOverride rule re-enables it even if
+// DisableCache is true.
+func (rb *RequestBuilder) effectiveDisableCache(reqURL string) bool {
+	rule, ok := rb.matchCacheRule(reqURL, rb.Headers)
+	if !ok {
+		return rb.DisableCache
+	}
+
+	return rule.Action == CacheRuleSkip
+}
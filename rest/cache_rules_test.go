@@ -0,0 +1,64 @@
+package rest
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCacheRuleMatchesPath(t *testing.T) {
+	rule := CacheRule{Type: CacheRulePath, Value: `^https://api\.example\.com/public/`}
+
+	if !rule.matches("https://api.example.com/public/widgets", nil) {
+		t.Error("expected the path rule to match")
+	}
+	if rule.matches("https://api.example.com/private/widgets", nil) {
+		t.Error("expected the path rule not to match")
+	}
+}
+
+func TestCacheRuleMatchesHeader(t *testing.T) {
+	rule := CacheRule{Type: CacheRuleHeader, HeaderKey: "Authorization", Value: "secret"}
+	header := http.Header{"Authorization": {"secret"}}
+
+	if !rule.matches("https://api.example.com/x", header) {
+		t.Error("expected the header rule to match")
+	}
+	if rule.matches("https://api.example.com/x", http.Header{}) {
+		t.Error("expected the header rule not to match an absent header")
+	}
+}
+
+func TestEffectiveDisableCacheSkipOverridesDefault(t *testing.T) {
+	rb := &RequestBuilder{
+		Headers: http.Header{"Authorization": {"secret"}},
+		CacheRules: []CacheRule{
+			{Type: CacheRuleHeader, HeaderKey: "Authorization", Value: "secret", Action: CacheRuleSkip},
+		},
+	}
+
+	if !rb.effectiveDisableCache("https://api.example.com/x") {
+		t.Error("expected a matching Skip rule to disable caching")
+	}
+}
+
+func TestEffectiveDisableCacheTTLOverrideReEnablesCaching(t *testing.T) {
+	rb := &RequestBuilder{
+		DisableCache: true,
+		CacheRules: []CacheRule{
+			{Type: CacheRulePath, Value: `^https://api\.example\.com/public/`, Action: CacheRuleTTLOverride, TTL: time.Minute},
+		},
+	}
+
+	if rb.effectiveDisableCache("https://api.example.com/public/widgets") {
+		t.Error("expected a matching TTLOverride rule to re-enable caching despite DisableCache")
+	}
+}
+
+func TestEffectiveDisableCacheNoRuleFallsBackToDisableCache(t *testing.T) {
+	rb := &RequestBuilder{DisableCache: true}
+
+	if !rb.effectiveDisableCache("https://api.example.com/x") {
+		t.Error("expected DisableCache to apply when no rule matches")
+	}
+}
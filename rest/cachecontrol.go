@@ -0,0 +1,88 @@
+package rest
+
+import (
+	"strconv"
+	"strings"
+)
+
+// cacheControl is the parsed set of Cache-Control directives carried by a
+// request or response header, per RFC 7234 §5.2.
+type cacheControl struct {
+	NoStore         bool
+	NoCache         bool
+	Private         bool
+	Public          bool
+	MustRevalidate  bool
+	ProxyRevalidate bool
+	Immutable       bool
+
+	MaxAge               *int
+	SMaxAge              *int
+	MinFresh             *int
+	MaxStale             *int
+	StaleWhileRevalidate *int
+	StaleIfError         *int
+}
+
+// parseCacheControl parses a Cache-Control header value into its directives.
+// Unknown or malformed directives are ignored rather than rejected, since
+// origins routinely send extension tokens this package doesn't act on.
+func parseCacheControl(header string) cacheControl {
+	var cc cacheControl
+
+	for _, part := range strings.Split(header, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, value := part, ""
+		if i := strings.IndexByte(part, '='); i >= 0 {
+			name, value = part[:i], strings.Trim(part[i+1:], `"`)
+		}
+
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "no-store":
+			cc.NoStore = true
+		case "no-cache":
+			cc.NoCache = true
+		case "private":
+			cc.Private = true
+		case "public":
+			cc.Public = true
+		case "must-revalidate":
+			cc.MustRevalidate = true
+		case "proxy-revalidate":
+			cc.ProxyRevalidate = true
+		case "immutable":
+			cc.Immutable = true
+		case "max-age":
+			cc.MaxAge = parseCacheSeconds(value)
+		case "s-maxage":
+			cc.SMaxAge = parseCacheSeconds(value)
+		case "min-fresh":
+			cc.MinFresh = parseCacheSeconds(value)
+		case "max-stale":
+			if value == "" {
+				unbounded := -1
+				cc.MaxStale = &unbounded
+			} else {
+				cc.MaxStale = parseCacheSeconds(value)
+			}
+		case "stale-while-revalidate":
+			cc.StaleWhileRevalidate = parseCacheSeconds(value)
+		case "stale-if-error":
+			cc.StaleIfError = parseCacheSeconds(value)
+		}
+	}
+
+	return cc
+}
+
+func parseCacheSeconds(value string) *int {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return nil
+	}
+	return &n
+}
@@ -0,0 +1,61 @@
+package rest
+
+import "testing"
+
+func TestParseCacheControlDirectives(t *testing.T) {
+	cc := parseCacheControl("no-cache, max-age=60, must-revalidate, stale-while-revalidate=30")
+
+	if !cc.NoCache {
+		t.Error("expected NoCache")
+	}
+	if !cc.MustRevalidate {
+		t.Error("expected MustRevalidate")
+	}
+	if cc.MaxAge == nil || *cc.MaxAge != 60 {
+		t.Errorf("expected MaxAge=60, got %v", cc.MaxAge)
+	}
+	if cc.StaleWhileRevalidate == nil || *cc.StaleWhileRevalidate != 30 {
+		t.Errorf("expected StaleWhileRevalidate=30, got %v", cc.StaleWhileRevalidate)
+	}
+}
+
+func TestParseCacheControlNoStore(t *testing.T) {
+	if cc := parseCacheControl("no-store"); !cc.NoStore {
+		t.Error("expected NoStore")
+	}
+}
+
+func TestParseCacheControlMaxAgeAndSMaxAge(t *testing.T) {
+	cc := parseCacheControl("max-age=10, s-maxage=20")
+
+	if cc.MaxAge == nil || *cc.MaxAge != 10 {
+		t.Errorf("expected MaxAge=10, got %v", cc.MaxAge)
+	}
+	if cc.SMaxAge == nil || *cc.SMaxAge != 20 {
+		t.Errorf("expected SMaxAge=20, got %v", cc.SMaxAge)
+	}
+}
+
+func TestVaryKeyIsOrderIndependent(t *testing.T) {
+	header := map[string][]string{"A": {"1"}, "B": {"2"}}
+
+	k1 := varyKey("https://api.example.com/x", header, "A, B")
+	k2 := varyKey("https://api.example.com/x", header, "B, A")
+
+	if k1 != k2 {
+		t.Errorf("expected order-independent vary keys, got %q vs %q", k1, k2)
+	}
+
+	if plain := varyKey("https://api.example.com/x", header, ""); plain != "https://api.example.com/x" {
+		t.Errorf("expected an empty Vary to leave the key unchanged, got %q", plain)
+	}
+}
+
+func TestVaryKeyDiffersByHeaderValue(t *testing.T) {
+	en := varyKey("https://api.example.com/x", map[string][]string{"Accept-Language": {"en"}}, "Accept-Language")
+	fr := varyKey("https://api.example.com/x", map[string][]string{"Accept-Language": {"fr"}}, "Accept-Language")
+
+	if en == fr {
+		t.Error("expected different Accept-Language values to produce different keys")
+	}
+}
@@ -0,0 +1,53 @@
+package rest
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// graphQLBody is the shape of a GraphQL-over-HTTP request body.
+type graphQLBody struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+// parseGraphQLBody reports whether body is a GraphQL operation (a JSON
+// object carrying a non-empty "query") and, if so, returns its query and
+// variables.
+func parseGraphQLBody(contentType ContentType, body []byte) (isGraphQL bool, query string, variables map[string]interface{}) {
+	if contentType != JSON || len(body) == 0 {
+		return false, "", nil
+	}
+
+	var gql graphQLBody
+	if err := json.Unmarshal(body, &gql); err != nil || gql.Query == "" {
+		return false, "", nil
+	}
+
+	return true, gql.Query, gql.Variables
+}
+
+// isGraphQLMutation reports whether body is a GraphQL operation, and if so
+// whether it is a mutation. Queries are cacheable; mutations aren't.
+func isGraphQLMutation(contentType ContentType, body []byte) (isGraphQL bool, mutation bool) {
+	isGraphQL, query, _ := parseGraphQLBody(contentType, body)
+	if !isGraphQL {
+		return false, false
+	}
+
+	return true, strings.HasPrefix(strings.TrimSpace(query), "mutation")
+}
+
+// graphQLCacheKey builds the cache key for a GraphQL query, normalizing the
+// query text and variables so equivalent requests collapse to one entry.
+func graphQLCacheKey(verb, url, query string, variables map[string]interface{}) string {
+	normalized, err := json.Marshal(graphQLBody{
+		Query:     strings.TrimSpace(query),
+		Variables: variables,
+	})
+	if err != nil {
+		return verb + " " + url + " " + query
+	}
+
+	return sha256CacheKey(verb, url, normalized)
+}
@@ -0,0 +1,67 @@
+package rest
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestIsGraphQLMutation(t *testing.T) {
+	isGQL, mutation := isGraphQLMutation(JSON, []byte(`{"query":"mutation { createWidget }","variables":{}}`))
+	if !isGQL || !mutation {
+		t.Fatalf("expected a detected mutation, got isGraphQL=%v mutation=%v", isGQL, mutation)
+	}
+
+	isGQL, mutation = isGraphQLMutation(JSON, []byte(`{"query":"query { widgets }","variables":{}}`))
+	if !isGQL || mutation {
+		t.Fatalf("expected a cacheable query, got isGraphQL=%v mutation=%v", isGQL, mutation)
+	}
+
+	if isGQL, _ := isGraphQLMutation(JSON, []byte(`{"foo":"bar"}`)); isGQL {
+		t.Error("expected a non-GraphQL body not to be classified as one")
+	}
+
+	if isGQL, _ := isGraphQLMutation(XML, []byte(`<query/>`)); isGQL {
+		t.Error("expected a non-JSON body not to be classified as GraphQL")
+	}
+}
+
+func TestGraphQLCacheKeyNormalizesWhitespace(t *testing.T) {
+	k1 := graphQLCacheKey(http.MethodPost, "https://api.example.com/graphql", "  query { widgets }  ", nil)
+	k2 := graphQLCacheKey(http.MethodPost, "https://api.example.com/graphql", "query { widgets }", nil)
+
+	if k1 != k2 {
+		t.Errorf("expected normalized keys to match, got %q vs %q", k1, k2)
+	}
+}
+
+func TestCacheableVerbGraphQLMutationNeverCacheable(t *testing.T) {
+	rb := &RequestBuilder{CachePOST: true, ContentType: JSON}
+
+	mutation := []byte(`{"query":"mutation { createWidget }","variables":{}}`)
+	if rb.cacheableVerb(http.MethodPost, mutation) {
+		t.Error("expected a GraphQL mutation not to be cacheable")
+	}
+
+	query := []byte(`{"query":"query { widgets }","variables":{}}`)
+	if !rb.cacheableVerb(http.MethodPost, query) {
+		t.Error("expected a GraphQL query to be cacheable when CachePOST is set")
+	}
+}
+
+func TestCacheableVerbPlainPostRequiresCachePOST(t *testing.T) {
+	if (&RequestBuilder{}).cacheableVerb(http.MethodPost, []byte(`{"a":1}`)) {
+		t.Error("expected POST not to be cacheable without CachePOST")
+	}
+
+	if !(&RequestBuilder{CachePOST: true}).cacheableVerb(http.MethodPost, []byte(`{"a":1}`)) {
+		t.Error("expected POST to be cacheable once CachePOST is set")
+	}
+}
+
+func TestCacheableVerbExcludesPatch(t *testing.T) {
+	rb := &RequestBuilder{CachePOST: true}
+
+	if rb.cacheableVerb(http.MethodPatch, []byte(`{"a":1}`)) {
+		t.Error("expected PATCH never to be cacheable, even with CachePOST set")
+	}
+}
@@ -0,0 +1,29 @@
+package rest
+
+import (
+	"net/url"
+	"os"
+)
+
+// mockUpEnvVar names the environment variable that, when set to a valid
+// URL, reroutes every outgoing request to a mock server while preserving
+// the original URL for caching and the X-Original-URL header.
+const mockUpEnvVar = "RESTFUL_MOCKUP_URL"
+
+var mockUpEnv bool
+var mockServerURL *url.URL
+
+func init() {
+	raw := os.Getenv(mockUpEnvVar)
+	if raw == "" {
+		return
+	}
+
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return
+	}
+
+	mockServerURL = parsed
+	mockUpEnv = true
+}
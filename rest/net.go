@@ -7,8 +7,6 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
-	"regexp"
-	"strconv"
 	"time"
 )
 
@@ -17,48 +15,99 @@ var transportCache = newSyncMap()
 var readVerbs = []string{http.MethodGet, http.MethodHead, http.MethodOptions}
 var contentVerbs = []string{http.MethodPost, http.MethodPut, http.MethodPatch}
 
-var maxAge = regexp.MustCompile(`(?:max-age|s-maxage)=(\d+)`)
-var httpDateFormat = "Mon, 01 Jan 2006 15:04:05 GMT"
+// cacheableContentVerbs are the non-GET-like verbs CachePOST can make
+// cacheable. Deliberately narrower than contentVerbs: PATCH isn't a
+// cacheable method under RFC 7231/7234, so it's excluded even though it
+// still carries a request body/content type.
+var cacheableContentVerbs = []string{http.MethodPost, http.MethodPut}
 
 func (rb *RequestBuilder) doRequest(verb string, reqURL string, reqBody interface{}) (response *Response) {
 
-	var cacheURL string
 	var cacheResp *Response
 
-	response = new(Response)
 	reqURL = rb.BaseURL + reqURL
 
-	//If Cache enable && operation is read: Cache GET
-	if !rb.DisableCache && match(verb, readVerbs) {
-		cacheResp = resourceCache.get(reqURL)
-		if cacheResp != nil && !cacheResp.revalidate {
-			return cacheResp
-		}
-	}
-
 	//Marshal request to JSON or XML
 	body, err := rb.marshalReqBody(reqBody)
 	if err != nil {
-		response.Err = err
-		return
+		return &Response{Err: err}
 	}
 
+	cache := rb.getCache()
+	reqCC := parseCacheControl(rb.Headers.Get("Cache-Control"))
+	disableCache := rb.effectiveDisableCache(reqURL)
+	cacheable := rb.cacheableVerb(verb, body)
+
+	//If Cache enable && operation is cacheable: look it up
+	if !disableCache && cacheable {
+		cacheKey := rb.resolveCacheKey(cache, verb, reqURL, body, rb.Headers)
+
+		// hit is the cache backend's own stored pointer, shared with every
+		// other concurrent caller for this key; clone it before any field on
+		// it is mutated below (see rest/response.go:clone).
+		if hit, ok := cache.Get(cacheKey); ok {
+			cacheResp = hit.clone()
+		}
+
+		if cacheResp != nil {
+			respCC := parseCacheControl(cacheResp.Header.Get("Cache-Control"))
+			if respCC.NoCache || respCC.MustRevalidate {
+				cacheResp.revalidate = true
+			}
+
+			now := time.Now()
+			expired := cacheResp.ttl != nil && now.After(*cacheResp.ttl)
+
+			switch {
+			case !expired && !cacheResp.revalidate:
+				markFromCache(cacheResp, false)
+				return cacheResp
+
+			case expired && respCC.StaleWhileRevalidate != nil &&
+				now.Before(cacheResp.ttl.Add(time.Duration(*respCC.StaleWhileRevalidate)*time.Second)):
+				// revalidateAsync races this goroutine's own mutation of
+				// cacheResp (markFromCache below), so it gets an independent
+				// clone to work with, flagged for revalidation so the
+				// background fetch sends the cached validators.
+				bg := cacheResp.clone()
+				bg.revalidate = true
+				rb.revalidateAsync(cacheKey, verb, reqURL, body, bg, reqCC)
+				markFromCache(cacheResp, true)
+				return cacheResp
+
+			case expired:
+				cacheResp.revalidate = true
+			}
+		}
+	}
+
+	return rb.fetch(verb, reqURL, body, cacheResp, reqCC)
+}
+
+// fetch performs the network round trip for reqURL, updates the cache
+// accordingly and returns the resulting Response. cacheResp, when non-nil,
+// drives the conditional request (If-None-Match / If-Modified-Since) and
+// serves as the stale-if-error fallback if the request fails.
+func (rb *RequestBuilder) fetch(verb string, reqURL string, body []byte, cacheResp *Response, reqCC cacheControl) (response *Response) {
+
+	response = new(Response)
+
 	// Change URL to point to Mockup server
-	reqURL, cacheURL, err = checkMockup(reqURL)
+	mockURL, cacheURL, err := checkMockup(reqURL)
 	if err != nil {
 		response.Err = err
 		return
 	}
 
 	//Get TCP connection (client + transport)
-	client, err := rb.connect(reqURL)
+	client, err := rb.connect(mockURL)
 	if err != nil {
 		response.Err = err
 		return
 	}
 
 	//Create request
-	request, err := http.NewRequest(verb, reqURL, bytes.NewBuffer(body))
+	request, err := http.NewRequest(verb, mockURL, bytes.NewBuffer(body))
 	if err != nil {
 		response.Err = err
 		return
@@ -70,6 +119,9 @@ func (rb *RequestBuilder) doRequest(verb string, reqURL string, reqBody interfac
 	// Make the request
 	httpResp, err := client.Do(request)
 	if err != nil {
+		if stale := staleIfError(cacheResp); stale != nil {
+			return stale
+		}
 		response.Err = err
 		return
 	}
@@ -82,12 +134,42 @@ func (rb *RequestBuilder) doRequest(verb string, reqURL string, reqBody interfac
 		return
 	}
 
-	// If we get a 304, return response from cache
+	// If we get a 304, overlay the origin's end-to-end headers onto the
+	// cached response, recompute its freshness and write it back.
 	if httpResp.StatusCode == http.StatusNotModified {
-		response = cacheResp
+		if cacheResp == nil {
+			response.Response = httpResp
+			return
+		}
+
+		merged := merge304(cacheResp, httpResp)
+
+		ttl := setTTL(merged)
+		lastModified := setLastModified(merged)
+		etag := setETag(merged)
+		if rule, ok := rb.matchCacheRule(reqURL, rb.Headers); ok && rule.Action == CacheRuleTTLOverride {
+			t := time.Now().Add(rule.TTL)
+			merged.ttl = &t
+			ttl = true
+		}
+		merged.revalidate = !ttl && (lastModified || etag)
+
+		cache := rb.getCache()
+		if !rb.effectiveDisableCache(reqURL) && rb.cacheableVerb(verb, body) && (ttl || lastModified || etag) {
+			rememberVary(cache, cacheURL, merged.Header.Get("Vary"))
+			cache.Set(rb.resolveCacheKey(cache, verb, cacheURL, body, rb.Headers), merged)
+		}
+
+		response = merged
 		return
 	}
 
+	if httpResp.StatusCode >= http.StatusInternalServerError {
+		if stale := staleIfError(cacheResp); stale != nil {
+			return stale
+		}
+	}
+
 	response.Response = httpResp
 	response.byteBody = respBody
 
@@ -99,9 +181,22 @@ func (rb *RequestBuilder) doRequest(verb string, reqURL string, reqBody interfac
 		response.revalidate = true
 	}
 
+	rule, hasRule := rb.matchCacheRule(reqURL, rb.Headers)
+	if hasRule && rule.Action == CacheRuleTTLOverride {
+		t := time.Now().Add(rule.TTL)
+		response.ttl = &t
+		ttl = true
+	}
+
 	//If Cache enable: Cache SETNX
-	if !rb.DisableCache && match(verb, readVerbs) && (ttl || lastModified || etag) {
-		resourceCache.setNX(cacheURL, response)
+	cache := rb.getCache()
+	respCC := parseCacheControl(response.Header.Get("Cache-Control"))
+	ignoreNoStore := hasRule && rule.Action == CacheRuleTTLOverride
+	disableCache := rb.effectiveDisableCache(reqURL)
+	cacheable := rb.cacheableVerb(verb, body)
+	if !disableCache && cacheable && (ttl || lastModified || etag) && (ignoreNoStore || (!reqCC.NoStore && !respCC.NoStore)) {
+		rememberVary(cache, cacheURL, response.Header.Get("Vary"))
+		cache.Set(rb.resolveCacheKey(cache, verb, cacheURL, body, rb.Headers), response)
 	}
 
 	return
@@ -235,7 +330,6 @@ func (rb *RequestBuilder) setParams(client *http.Client, req *http.Request, cach
 
 	//Default headers
 	req.Header.Set("Connection", "keep-alive")
-	req.Header.Set("Cache-Control", "no-cache")
 
 	//If mockup
 	if mockUpEnv {
@@ -244,7 +338,10 @@ func (rb *RequestBuilder) setParams(client *http.Client, req *http.Request, cach
 
 	//Custom Headers
 	if rb.Headers != nil {
-		req.Header = rb.Headers
+		// Clone rather than alias: rb.Headers is shared by every request this
+		// builder makes, including concurrent stale-while-revalidate
+		// background fetches, and req.Header.Set below mutates it in place.
+		req.Header = cloneHeader(rb.Headers)
 	}
 
 	//Encoding
@@ -268,7 +365,7 @@ func (rb *RequestBuilder) setParams(client *http.Client, req *http.Request, cach
 		case cacheResp.etag != "":
 			req.Header.Set("If-None-Match", cacheResp.etag)
 		case cacheResp.lastModified != nil:
-			req.Header.Set("If-Modified-Since", cacheResp.lastModified.Format(httpDateFormat))
+			req.Header.Set("If-Modified-Since", cacheResp.lastModified.Format(http.TimeFormat))
 		}
 	}
 
@@ -289,16 +386,21 @@ func setTTL(resp *Response) (set bool) {
 
 	now := time.Now()
 
-	//Cache-Control Header
-	cacheControl := maxAge.FindStringSubmatch(resp.Header.Get("Cache-Control"))
+	cc := parseCacheControl(resp.Header.Get("Cache-Control"))
 
-	if len(cacheControl) > 1 {
+	if cc.NoStore {
+		return
+	}
 
-		ttl, err := strconv.Atoi(cacheControl[1])
-		if err != nil {
-			return
-		}
+	//Cache-Control Header: s-maxage takes precedence over max-age
+	maxAge := cc.MaxAge
+	if cc.SMaxAge != nil {
+		maxAge = cc.SMaxAge
+	}
 
+	if maxAge != nil {
+
+		ttl := *maxAge - responseAge(resp, now)
 		if ttl > 0 {
 			t := now.Add(time.Duration(ttl) * time.Second)
 			resp.ttl = &t
@@ -309,8 +411,9 @@ func setTTL(resp *Response) (set bool) {
 	}
 
 	//Expires Header
-	//Date format from RFC-2616, Section 14.21
-	expires, err := time.Parse(httpDateFormat, resp.Header.Get("Expires"))
+	//http.ParseTime accepts RFC 1123, RFC 850 and ANSI C asctime, since real
+	//origins emit all three formats for Expires.
+	expires, err := http.ParseTime(resp.Header.Get("Expires"))
 	if err != nil {
 		return
 	}
@@ -323,8 +426,26 @@ func setTTL(resp *Response) (set bool) {
 	return
 }
 
+// responseAge returns resp's apparent age per RFC 7234 §4.2.3: how long ago
+// the origin generated it, based on its Date header. Responses without a
+// usable Date header are treated as freshly generated.
+func responseAge(resp *Response, now time.Time) int {
+	date, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return 0
+	}
+
+	if age := int(now.Sub(date).Seconds()); age > 0 {
+		return age
+	}
+
+	return 0
+}
+
 func setLastModified(resp *Response) bool {
-	lastModified, err := time.Parse(httpDateFormat, resp.Header.Get("Last-Modified"))
+	// http.ParseTime accepts RFC 1123, RFC 850 and ANSI C asctime, since
+	// real origins emit all three formats for Last-Modified.
+	lastModified, err := http.ParseTime(resp.Header.Get("Last-Modified"))
 	if err != nil {
 		return false
 	}
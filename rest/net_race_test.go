@@ -0,0 +1,83 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestDoRequestConcurrentCacheHitsDontRace reproduces the data race where a
+// cache hit's *Response was mutated (X-From-Cache, revalidate) in place,
+// racing every other goroutine reading the same stored pointer. Run with
+// -race.
+func TestDoRequestConcurrentCacheHitsDontRace(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Header().Set("Date", time.Now().UTC().Format(http.TimeFormat))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	rb := &RequestBuilder{}
+
+	// Prime the cache.
+	rb.doRequest(http.MethodGet, server.URL, nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rb.doRequest(http.MethodGet, server.URL, nil)
+		}()
+	}
+	wg.Wait()
+}
+
+// TestDoRequestStaleWhileRevalidateDoesntRaceHeaders reproduces the data race
+// where setParams aliased rb.Headers into the outgoing request instead of
+// cloning it, so a background stale-while-revalidate fetch (revalidateAsync)
+// mutating that map via Header.Set raced a concurrent synchronous fetch on
+// the same builder doing the same thing. Run with -race.
+func TestDoRequestStaleWhileRevalidateDoesntRaceHeaders(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/swr", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=1, stale-while-revalidate=60")
+		w.Header().Set("Date", time.Now().UTC().Format(http.TimeFormat))
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/direct", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	rb := &RequestBuilder{Headers: http.Header{"Authorization": {"secret"}}}
+
+	// Prime the cache, then let max-age=1 elapse so the entry is stale but
+	// still SWR-eligible.
+	rb.doRequest(http.MethodGet, server.URL+"/swr", nil)
+	time.Sleep(1100 * time.Millisecond)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Triggers a background revalidateAsync fetch, which calls
+			// setParams concurrently with the loop below.
+			rb.doRequest(http.MethodGet, server.URL+"/swr", nil)
+		}()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Never cached (no freshness headers), so every call runs
+			// fetch/setParams synchronously on the same rb.Headers.
+			rb.doRequest(http.MethodGet, server.URL+"/direct", nil)
+		}()
+	}
+	wg.Wait()
+}
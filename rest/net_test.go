@@ -0,0 +1,96 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSetTTLAcceptsNonRFC1123Expires(t *testing.T) {
+	future := time.Now().Add(time.Hour).UTC()
+
+	resp := &Response{Response: &http.Response{Header: http.Header{
+		// RFC 850 format, as some origins still emit.
+		"Expires": {future.Format("Monday, 02-Jan-06 15:04:05 MST")},
+	}}}
+
+	if !setTTL(resp) {
+		t.Fatal("expected an RFC 850 Expires header to be parsed")
+	}
+}
+
+func TestResponseAgeAcceptsNonRFC1123Date(t *testing.T) {
+	past := time.Now().Add(-30 * time.Second).UTC()
+
+	resp := &Response{Response: &http.Response{Header: http.Header{
+		"Date": {past.Format("Monday, 02-Jan-06 15:04:05 MST")},
+	}}}
+
+	if age := responseAge(resp, time.Now()); age < 25 || age > 35 {
+		t.Fatalf("expected an age around 30s, got %d", age)
+	}
+}
+
+// TestFetch304MergesHeadersAndRefreshesCache covers the headline chunk0-6
+// behavior end-to-end: a 304 overlays the origin's headers onto the cached
+// entry, recomputes its freshness, and writes the merged entry back to the
+// cache rather than just handing it back to the caller.
+func TestFetch304MergesHeadersAndRefreshesCache(t *testing.T) {
+	var calls int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("ETag", `"v1"`)
+			w.Header().Set("Date", time.Now().UTC().Format(http.TimeFormat))
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("cached body"))
+			return
+		}
+
+		if got := r.Header.Get("If-None-Match"); got != `"v1"` {
+			t.Errorf("expected a conditional request carrying the cached ETag, got If-None-Match=%q", got)
+		}
+
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Header().Set("Date", time.Now().UTC().Format(http.TimeFormat))
+		w.Header().Set("X-Revalidated", "yes")
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer server.Close()
+
+	rb := &RequestBuilder{Cache: newResponseCache()}
+
+	first := rb.doRequest(http.MethodGet, server.URL, nil)
+	if string(first.Bytes()) != "cached body" {
+		t.Fatalf("expected the priming request's body to be cached, got %q", first.Bytes())
+	}
+
+	// No ttl was set on the first response (only an ETag), so the entry
+	// needs revalidation on the very next request.
+	second := rb.doRequest(http.MethodGet, server.URL, nil)
+	if string(second.Bytes()) != "cached body" {
+		t.Errorf("expected the 304 to keep serving the cached body, got %q", second.Bytes())
+	}
+	if got := second.Header.Get("X-Revalidated"); got != "yes" {
+		t.Errorf("expected merge304 to overlay the 304's own headers, got X-Revalidated=%q", got)
+	}
+	if second.revalidate {
+		t.Error("expected the merged entry to be fresh (max-age=60) and no longer need revalidation")
+	}
+
+	key := rb.resolveCacheKey(rb.Cache, http.MethodGet, server.URL, nil, rb.Headers)
+	cached, ok := rb.Cache.Get(key)
+	if !ok {
+		t.Fatal("expected the merged 304 response to be written back to the cache")
+	}
+	if cached.ttl == nil || !cached.ttl.After(time.Now()) {
+		t.Error("expected the merged entry's ttl to be recomputed from the 304's max-age")
+	}
+
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Fatalf("expected exactly 2 origin requests (prime + revalidate), got %d", got)
+	}
+}
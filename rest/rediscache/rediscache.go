@@ -0,0 +1,57 @@
+// Package rediscache provides a rest.Cache backend stored in Redis. It is
+// kept out of the main rest package so consumers that don't need it aren't
+// forced to compile in the Redis client and its transitive dependencies.
+package rediscache
+
+import (
+	"context"
+	"time"
+
+	"github.com/PabloRai/restful/rest"
+	"github.com/go-redis/redis/v8"
+)
+
+// Cache is a rest.Cache backend that stores responses in Redis, allowing
+// cached responses to be shared across processes and hosts.
+type Cache struct {
+	Client *redis.Client
+
+	// TTL bounds how long an entry lives in Redis regardless of the
+	// response's own freshness lifetime. Zero means "no expiration".
+	TTL time.Duration
+}
+
+// New returns a Cache backed by client.
+func New(client *redis.Client) *Cache {
+	return &Cache{Client: client}
+}
+
+// Get implements rest.Cache.
+func (c *Cache) Get(key string) (*rest.Response, bool) {
+	data, err := c.Client.Get(context.Background(), key).Bytes()
+	if err != nil {
+		return nil, false
+	}
+
+	resp, err := rest.UnmarshalCache(data)
+	if err != nil {
+		return nil, false
+	}
+
+	return resp, true
+}
+
+// Set implements rest.Cache.
+func (c *Cache) Set(key string, resp *rest.Response) {
+	data, err := resp.MarshalCache()
+	if err != nil {
+		return
+	}
+
+	c.Client.Set(context.Background(), key, data, c.TTL)
+}
+
+// Delete implements rest.Cache.
+func (c *Cache) Delete(key string) {
+	c.Client.Del(context.Background(), key)
+}
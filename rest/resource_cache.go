@@ -0,0 +1,43 @@
+package rest
+
+// resourceCache is the package's default in-process Cache implementation. It
+// stores responses for cacheable requests so doRequest can serve them
+// without round-tripping to the origin while they are still fresh.
+var resourceCache = newResponseCache()
+
+type responseCache struct {
+	sm *syncMap
+}
+
+func newResponseCache() *responseCache {
+	return &responseCache{sm: newSyncMap()}
+}
+
+func (c *responseCache) get(key string) *Response {
+	resp, _ := c.sm.get(key).(*Response)
+	return resp
+}
+
+func (c *responseCache) setNX(key string, resp *Response) bool {
+	return c.sm.setNX(key, resp)
+}
+
+func (c *responseCache) delete(key string) {
+	c.sm.delete(key)
+}
+
+// Get implements Cache.
+func (c *responseCache) Get(key string) (*Response, bool) {
+	resp := c.get(key)
+	return resp, resp != nil
+}
+
+// Set implements Cache.
+func (c *responseCache) Set(key string, resp *Response) {
+	c.sm.set(key, resp)
+}
+
+// Delete implements Cache.
+func (c *responseCache) Delete(key string) {
+	c.delete(key)
+}
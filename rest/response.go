@@ -0,0 +1,57 @@
+package rest
+
+import (
+	"net/http"
+	"time"
+)
+
+// Response wraps the result of a RequestBuilder call, adding the metadata
+// the package needs to drive HTTP caching on top of the raw *http.Response.
+type Response struct {
+	*http.Response
+	Err error
+
+	// Stale reports whether this Response is a cached entry served past its
+	// freshness lifetime, per a stale-while-revalidate or stale-if-error
+	// Cache-Control directive, rather than a fresh hit or a live fetch.
+	Stale bool
+
+	byteBody []byte
+
+	ttl          *time.Time
+	etag         string
+	lastModified *time.Time
+	revalidate   bool
+}
+
+// Bytes returns the raw response body read off the wire.
+func (r *Response) Bytes() []byte {
+	return r.byteBody
+}
+
+// clone returns a deep copy of r, including its header, so a cache hit can
+// be mutated (X-From-Cache, revalidate, ...) by its caller without racing
+// other concurrent readers of the same cached entry.
+func (r *Response) clone() *Response {
+	if r == nil {
+		return nil
+	}
+
+	c := &Response{
+		Err:          r.Err,
+		Stale:        r.Stale,
+		byteBody:     r.byteBody,
+		ttl:          r.ttl,
+		etag:         r.etag,
+		lastModified: r.lastModified,
+		revalidate:   r.revalidate,
+	}
+
+	if r.Response != nil {
+		httpResp := *r.Response
+		httpResp.Header = cloneHeader(r.Header)
+		c.Response = &httpResp
+	}
+
+	return c
+}
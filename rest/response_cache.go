@@ -0,0 +1,63 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/gob"
+	"net/http"
+	"time"
+)
+
+// cacheEntry is the serializable projection of a Response used by external
+// Cache backends (Redis, disk, ...) that cannot store *http.Response as-is.
+type cacheEntry struct {
+	StatusCode   int
+	Header       http.Header
+	Body         []byte
+	TTL          *time.Time
+	ETag         string
+	LastModified *time.Time
+	Revalidate   bool
+}
+
+// MarshalCache serializes the parts of a Response an external Cache backend
+// needs to reconstruct it later: status, headers, body and cache metadata
+// (ttl, etag, last-modified, revalidation flag).
+func (r *Response) MarshalCache() ([]byte, error) {
+	entry := cacheEntry{
+		Header:       r.Header,
+		Body:         r.byteBody,
+		TTL:          r.ttl,
+		ETag:         r.etag,
+		LastModified: r.lastModified,
+		Revalidate:   r.revalidate,
+	}
+	if r.Response != nil {
+		entry.StatusCode = r.StatusCode
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(entry); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalCache rebuilds a Response from bytes produced by MarshalCache.
+func UnmarshalCache(data []byte) (*Response, error) {
+	var entry cacheEntry
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&entry); err != nil {
+		return nil, err
+	}
+
+	return &Response{
+		Response: &http.Response{
+			StatusCode: entry.StatusCode,
+			Header:     entry.Header,
+		},
+		byteBody:     entry.Body,
+		ttl:          entry.TTL,
+		etag:         entry.ETag,
+		lastModified: entry.LastModified,
+		revalidate:   entry.Revalidate,
+	}, nil
+}
@@ -0,0 +1,36 @@
+package rest
+
+import "net/http"
+
+// merge304 rebuilds the cached Response for a 304 Not Modified result,
+// overlaying the end-to-end headers the origin sent on cacheResp per
+// RFC 7234 §4.3.4, so a revalidated entry's freshness lifetime is extended
+// rather than recomputed from the stale headers it was first cached with.
+func merge304(cacheResp *Response, httpResp *http.Response) *Response {
+	merged := &Response{
+		Response: &http.Response{
+			StatusCode: cacheResp.StatusCode,
+			Header:     cloneHeader(cacheResp.Header),
+		},
+		byteBody: cacheResp.byteBody,
+	}
+
+	for key, values := range httpResp.Header {
+		switch key {
+		case "Connection", "Keep-Alive", "Proxy-Authenticate", "Proxy-Authorization", "Te", "Trailer", "Transfer-Encoding", "Upgrade":
+			// Hop-by-hop headers describe the 304's own connection, not the entry.
+			continue
+		}
+		merged.Header[key] = values
+	}
+
+	return merged
+}
+
+func cloneHeader(h http.Header) http.Header {
+	clone := make(http.Header, len(h))
+	for key, values := range h {
+		clone[key] = append([]string(nil), values...)
+	}
+	return clone
+}
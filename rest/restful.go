@@ -0,0 +1,60 @@
+package rest
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ContentType represents the wire format used to marshal requests and
+// unmarshal responses.
+type ContentType int
+
+const (
+	// JSON marshals/unmarshals request and response bodies as JSON.
+	JSON ContentType = iota
+	// XML marshals/unmarshals request and response bodies as XML.
+	XML
+)
+
+// DefaultTimeout is used whenever neither RequestBuilder.Timeout nor
+// RequestBuilder.DisableTimeout has been set.
+const DefaultTimeout = 2 * time.Second
+
+// RequestBuilder is the entry point to perform HTTP requests against an API.
+// Its zero value is ready to use; BaseURL, Headers, ContentType and the
+// remaining fields tune its behaviour.
+type RequestBuilder struct {
+	BaseURL     string
+	ContentType ContentType
+	Headers     http.Header
+	Proxy       string
+
+	Timeout        time.Duration
+	DisableTimeout bool
+
+	MaxIdleConnsPerHost int
+
+	// DisableCache turns off response caching for this builder, except for
+	// requests that a matching Cache or TTLOverride rule in CacheRules
+	// re-enables.
+	DisableCache bool
+
+	// CacheRules lets specific endpoints opt in or out of caching, or pin a
+	// fixed TTL, overriding both DisableCache and the origin's own
+	// Cache-Control/Expires headers. Evaluated in order; first match wins.
+	CacheRules []CacheRule
+
+	// Cache overrides the backend used to store cacheable responses. When
+	// nil, the package-level in-process cache is used.
+	Cache Cache
+
+	// CachePOST opts POST/PUT requests with idempotent bodies (e.g. search
+	// endpoints, GraphQL queries) into the response cache, keyed by the
+	// request body's hash. GraphQL mutations are never cached.
+	CachePOST bool
+
+	clientCache atomic.Value
+	rwMutex     sync.RWMutex
+}
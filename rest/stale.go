@@ -0,0 +1,93 @@
+package rest
+
+import (
+	"sync"
+	"time"
+)
+
+// revalidateWorkers bounds how many background stale-while-revalidate
+// refreshes can run at once across the whole process.
+var revalidateWorkers = make(chan struct{}, 16)
+
+// revalidating deduplicates background refreshes so a burst of requests for
+// the same expired key triggers only one in-flight revalidation.
+var revalidating = struct {
+	mu       sync.Mutex
+	inFlight map[string]bool
+}{inFlight: make(map[string]bool)}
+
+func tryStartRevalidation(key string) bool {
+	revalidating.mu.Lock()
+	defer revalidating.mu.Unlock()
+
+	if revalidating.inFlight[key] {
+		return false
+	}
+	revalidating.inFlight[key] = true
+	return true
+}
+
+func finishRevalidation(key string) {
+	revalidating.mu.Lock()
+	defer revalidating.mu.Unlock()
+	delete(revalidating.inFlight, key)
+}
+
+// revalidateAsync kicks off a background conditional request for reqURL so a
+// stale-while-revalidate hit can be served immediately while freshness is
+// restored out of band. Redundant callers for the same cacheKey are no-ops;
+// cacheKey must be the resolved cache key (see resolveCacheKey), not reqURL,
+// since distinct Vary/body variants of the same URL revalidate independently.
+func (rb *RequestBuilder) revalidateAsync(cacheKey string, verb, reqURL string, body []byte, cacheResp *Response, reqCC cacheControl) {
+	if !tryStartRevalidation(cacheKey) {
+		return
+	}
+
+	go func() {
+		defer finishRevalidation(cacheKey)
+
+		revalidateWorkers <- struct{}{}
+		defer func() { <-revalidateWorkers }()
+
+		rb.fetch(verb, reqURL, body, cacheResp, reqCC)
+	}()
+}
+
+// markFromCache tags resp as served from cache, setting X-From-Cache to
+// "stale" for a stale-while-revalidate/stale-if-error hit, or "1" for an
+// ordinary fresh hit.
+func markFromCache(resp *Response, stale bool) {
+	if resp == nil || resp.Header == nil {
+		return
+	}
+
+	resp.Stale = stale
+	if stale {
+		resp.Header.Set("X-From-Cache", "stale")
+		return
+	}
+
+	resp.Header.Set("X-From-Cache", "1")
+}
+
+// staleIfError returns cacheResp, marked stale, when it is still within the
+// stale-if-error window recorded on its own Cache-Control header. It returns
+// nil when there is no usable stale fallback for the failed request.
+func staleIfError(cacheResp *Response) *Response {
+	if cacheResp == nil || cacheResp.ttl == nil {
+		return nil
+	}
+
+	cc := parseCacheControl(cacheResp.Header.Get("Cache-Control"))
+	if cc.StaleIfError == nil {
+		return nil
+	}
+
+	deadline := cacheResp.ttl.Add(time.Duration(*cc.StaleIfError) * time.Second)
+	if time.Now().After(deadline) {
+		return nil
+	}
+
+	markFromCache(cacheResp, true)
+	return cacheResp
+}
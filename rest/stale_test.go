@@ -0,0 +1,103 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStaleIfErrorWithinWindow(t *testing.T) {
+	ttl := time.Now().Add(-10 * time.Second)
+	resp := &Response{
+		Response: &http.Response{Header: http.Header{"Cache-Control": {"stale-if-error=60"}}},
+		ttl:      &ttl,
+	}
+
+	stale := staleIfError(resp)
+	if stale == nil {
+		t.Fatal("expected a stale-if-error fallback")
+	}
+	if !stale.Stale {
+		t.Error("expected Stale to be true")
+	}
+	if got := stale.Header.Get("X-From-Cache"); got != "stale" {
+		t.Errorf("expected X-From-Cache: stale, got %q", got)
+	}
+}
+
+func TestStaleIfErrorOutsideWindow(t *testing.T) {
+	ttl := time.Now().Add(-120 * time.Second)
+	resp := &Response{
+		Response: &http.Response{Header: http.Header{"Cache-Control": {"stale-if-error=60"}}},
+		ttl:      &ttl,
+	}
+
+	if stale := staleIfError(resp); stale != nil {
+		t.Error("expected no fallback once past the stale-if-error window")
+	}
+}
+
+func TestStaleIfErrorWithoutDirective(t *testing.T) {
+	ttl := time.Now().Add(-10 * time.Second)
+	resp := &Response{Response: &http.Response{Header: http.Header{}}, ttl: &ttl}
+
+	if stale := staleIfError(resp); stale != nil {
+		t.Error("expected nil without a stale-if-error directive")
+	}
+}
+
+// TestDoRequestStaleWhileRevalidateServesStaleAndRefreshesCache covers the
+// headline chunk0-3 behavior end-to-end: an expired-but-within-window hit is
+// served immediately from cache, while a background request transparently
+// refreshes the cache entry for the next caller.
+func TestDoRequestStaleWhileRevalidateServesStaleAndRefreshesCache(t *testing.T) {
+	var hits int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=1, stale-while-revalidate=60")
+		w.Header().Set("Date", time.Now().UTC().Format(http.TimeFormat))
+		w.WriteHeader(http.StatusOK)
+		if atomic.AddInt32(&hits, 1) == 1 {
+			w.Write([]byte("v1"))
+		} else {
+			w.Write([]byte("v2"))
+		}
+	}))
+	defer server.Close()
+
+	rb := &RequestBuilder{Cache: newResponseCache()}
+
+	first := rb.doRequest(http.MethodGet, server.URL, nil)
+	if string(first.Bytes()) != "v1" {
+		t.Fatalf("expected the priming request to see v1, got %q", first.Bytes())
+	}
+
+	// Let max-age=1 elapse so the entry is expired; stale-while-revalidate=60
+	// keeps it eligible for an immediate stale hit plus a background refresh.
+	time.Sleep(1100 * time.Millisecond)
+	second := rb.doRequest(http.MethodGet, server.URL, nil)
+	if string(second.Bytes()) != "v1" {
+		t.Fatalf("expected the stale-while-revalidate hit to serve the cached v1 immediately, got %q", second.Bytes())
+	}
+	if !second.Stale {
+		t.Error("expected the stale-while-revalidate hit to be marked Stale")
+	}
+	if got := second.Header.Get("X-From-Cache"); got != "stale" {
+		t.Errorf("expected X-From-Cache: stale, got %q", got)
+	}
+
+	key := rb.resolveCacheKey(rb.Cache, http.MethodGet, server.URL, nil, rb.Headers)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if cached, ok := rb.Cache.Get(key); ok && string(cached.Bytes()) == "v2" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the background revalidation to refresh the cache with v2")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
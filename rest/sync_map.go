@@ -0,0 +1,45 @@
+package rest
+
+import "sync"
+
+// syncMap is a minimal concurrency-safe string-keyed map used for the
+// package's internal caches (HTTP transports, clients and, by default,
+// cached responses).
+type syncMap struct {
+	mu sync.RWMutex
+	m  map[string]interface{}
+}
+
+func newSyncMap() *syncMap {
+	return &syncMap{m: make(map[string]interface{})}
+}
+
+func (s *syncMap) get(key string) interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m[key]
+}
+
+func (s *syncMap) set(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m[key] = value
+}
+
+// setNX sets key only if it is not already present, reporting whether the
+// value was stored.
+func (s *syncMap) setNX(key string, value interface{}) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.m[key]; ok {
+		return false
+	}
+	s.m[key] = value
+	return true
+}
+
+func (s *syncMap) delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.m, key)
+}